@@ -0,0 +1,34 @@
+package vmware
+
+import (
+	"fmt"
+	"os"
+)
+
+// Artifact is the result of running the VMware builder, returned as a
+// directory alongside every file it contains: the output directory, or
+// its export subdirectory when the build was exported to OVF/OVA.
+type Artifact struct {
+	dir string
+	f   []string
+}
+
+func (*Artifact) BuilderId() string {
+	return BuilderId
+}
+
+func (a *Artifact) Files() []string {
+	return a.f
+}
+
+func (*Artifact) Id() string {
+	return ""
+}
+
+func (a *Artifact) String() string {
+	return fmt.Sprintf("VM files in directory: %s", a.dir)
+}
+
+func (a *Artifact) Destroy() error {
+	return os.RemoveAll(a.dir)
+}
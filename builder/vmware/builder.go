@@ -21,25 +21,42 @@ type Builder struct {
 	runner multistep.Runner
 }
 
+// additionalDiskConfig describes one entry of the additional_disks list,
+// each of which becomes its own SCSI disk alongside the primary one.
+type additionalDiskConfig struct {
+	Name string `mapstructure:"name"`
+	Size uint   `mapstructure:"size"`
+	Type string `mapstructure:"type"`
+}
+
 type config struct {
-	DiskName        string            `mapstructure:"vmdk_name"`
-	GuestOSType     string            `mapstructure:"guest_os_type"`
-	ISOUrl          string            `mapstructure:"iso_url"`
-	VMName          string            `mapstructure:"vm_name"`
-	OutputDir       string            `mapstructure:"output_directory"`
-	HTTPDir         string            `mapstructure:"http_directory"`
-	HTTPPortMin     uint              `mapstructure:"http_port_min"`
-	HTTPPortMax     uint              `mapstructure:"http_port_max"`
-	BootCommand     []string          `mapstructure:"boot_command"`
-	BootWait        time.Duration     ``
-	ShutdownCommand string            `mapstructure:"shutdown_command"`
-	ShutdownTimeout time.Duration     ``
-	SSHUser         string            `mapstructure:"ssh_username"`
-	SSHPassword     string            `mapstructure:"ssh_password"`
-	SSHWaitTimeout  time.Duration     ``
-	VMXData         map[string]string `mapstructure:"vmx_data"`
-	VNCPortMin      uint              `mapstructure:"vnc_port_min"`
-	VNCPortMax      uint              `mapstructure:"vnc_port_max"`
+	AdditionalDisks []additionalDiskConfig `mapstructure:"additional_disks"`
+	DiskName        string                 `mapstructure:"vmdk_name"`
+	DiskSize        uint                   `mapstructure:"disk_size"`
+	DiskTypeId      string                 `mapstructure:"disk_type_id"`
+	FloppyFiles     []string               `mapstructure:"floppy_files"`
+	Format          string                 `mapstructure:"format"`
+	GuestOSType     string                 `mapstructure:"guest_os_type"`
+	ISOUrl          string                 `mapstructure:"iso_url"`
+	VMName          string                 `mapstructure:"vm_name"`
+	OutputDir       string                 `mapstructure:"output_directory"`
+	HTTPDir         string                 `mapstructure:"http_directory"`
+	HTTPPortMin     uint                   `mapstructure:"http_port_min"`
+	HTTPPortMax     uint                   `mapstructure:"http_port_max"`
+	BootCommand     []string               `mapstructure:"boot_command"`
+	BootWait        time.Duration          ``
+	ShutdownCommand string                 `mapstructure:"shutdown_command"`
+	ShutdownTimeout time.Duration          ``
+	SSHUser         string                 `mapstructure:"ssh_username"`
+	SSHPassword     string                 `mapstructure:"ssh_password"`
+	SSHWaitTimeout  time.Duration          ``
+	VMXData         map[string]string      `mapstructure:"vmx_data"`
+	VNCPortMin      uint                   `mapstructure:"vnc_port_min"`
+	VNCPortMax      uint                   `mapstructure:"vnc_port_max"`
+
+	DriverType      string `mapstructure:"driver"`
+	FusionAppPath   string `mapstructure:"fusion_app_path"`
+	ToolsUploadPath string `mapstructure:"tools_path"`
 
 	RawBootWait        string `mapstructure:"boot_wait"`
 	RawShutdownTimeout string `mapstructure:"shutdown_timeout"`
@@ -56,6 +73,14 @@ func (b *Builder) Prepare(raw interface{}) (err error) {
 		b.config.DiskName = "disk"
 	}
 
+	if b.config.DiskSize == 0 {
+		b.config.DiskSize = 40000
+	}
+
+	if b.config.DiskTypeId == "" {
+		b.config.DiskTypeId = "2GbMaxExtentSparse"
+	}
+
 	if b.config.GuestOSType == "" {
 		b.config.GuestOSType = "other"
 	}
@@ -84,6 +109,10 @@ func (b *Builder) Prepare(raw interface{}) (err error) {
 		b.config.OutputDir = "vmware"
 	}
 
+	if b.config.Format == "" {
+		b.config.Format = "vmx"
+	}
+
 	// Accumulate any errors
 	errs := make([]error, 0)
 
@@ -99,6 +128,43 @@ func (b *Builder) Prepare(raw interface{}) (err error) {
 		errs = append(errs, errors.New("An ssh_username must be specified."))
 	}
 
+	if b.config.Format != "vmx" && b.config.Format != "ovf" && b.config.Format != "ova" {
+		errs = append(errs, fmt.Errorf("format must be one of: vmx, ovf, ova"))
+	}
+
+	if _, err := diskTypeIdToVdiskManagerType(b.config.DiskTypeId); err != nil {
+		errs = append(errs, err)
+	}
+
+	diskNames := map[string]int{b.config.DiskName: -1}
+	for i := range b.config.AdditionalDisks {
+		additional := &b.config.AdditionalDisks[i]
+
+		if additional.Name == "" {
+			errs = append(errs, fmt.Errorf("additional_disks[%d]: name is required", i))
+		} else if other, ok := diskNames[additional.Name]; ok {
+			if other == -1 {
+				errs = append(errs, fmt.Errorf("additional_disks[%d]: name %q collides with vmdk_name", i, additional.Name))
+			} else {
+				errs = append(errs, fmt.Errorf("additional_disks[%d]: name %q collides with additional_disks[%d]", i, additional.Name, other))
+			}
+		} else {
+			diskNames[additional.Name] = i
+		}
+
+		if additional.Size == 0 {
+			errs = append(errs, fmt.Errorf("additional_disks[%d]: size is required", i))
+		}
+
+		if additional.Type == "" {
+			additional.Type = b.config.DiskTypeId
+		}
+
+		if _, err := diskTypeIdToVdiskManagerType(additional.Type); err != nil {
+			errs = append(errs, fmt.Errorf("additional_disks[%d]: %s", i, err))
+		}
+	}
+
 	if b.config.RawBootWait != "" {
 		b.config.BootWait, err = time.ParseDuration(b.config.RawBootWait)
 		if err != nil {
@@ -130,7 +196,12 @@ func (b *Builder) Prepare(raw interface{}) (err error) {
 
 	b.driver, err = b.newDriver()
 	if err != nil {
-		errs = append(errs, fmt.Errorf("Failed creating VMware driver: %s", err))
+		if multiErr, ok := err.(*packer.MultiError); ok {
+			errs = append(errs, fmt.Errorf("No supported VMware product could be found:"))
+			errs = append(errs, multiErr.Errors...)
+		} else {
+			errs = append(errs, fmt.Errorf("Failed creating VMware driver: %s", err))
+		}
 	}
 
 	if len(errs) > 0 {
@@ -147,6 +218,7 @@ func (b *Builder) Run(ui packer.Ui, hook packer.Hook) packer.Artifact {
 	steps := []multistep.Step{
 		&stepPrepareOutputDir{},
 		&stepCreateDisk{},
+		&stepCreateFloppy{},
 		&stepCreateVMX{},
 		&stepHTTPServer{},
 		&stepConfigureVNC{},
@@ -155,6 +227,7 @@ func (b *Builder) Run(ui packer.Ui, hook packer.Hook) packer.Artifact {
 		&stepWaitForSSH{},
 		&stepProvision{},
 		&stepShutdown{},
+		&stepExportOVF{},
 	}
 
 	// Setup the state bag
@@ -177,19 +250,26 @@ func (b *Builder) Run(ui packer.Ui, hook packer.Hook) packer.Artifact {
 		return nil
 	}
 
-	// Compile the artifact list
+	// Compile the artifact list. If the build was exported to OVF/OVA,
+	// the artifact reflects the exported files instead of the raw VMX
+	// output directory.
+	artifactDir := b.config.OutputDir
+	if exportPathRaw, ok := state["export_path"]; ok {
+		artifactDir = exportPathRaw.(string)
+	}
+
 	files := make([]string, 0, 10)
 	visit := func(path string, info os.FileInfo, err error) error {
 		files = append(files, path)
 		return err
 	}
 
-	if err := filepath.Walk(b.config.OutputDir, visit); err != nil {
+	if err := filepath.Walk(artifactDir, visit); err != nil {
 		ui.Error(fmt.Sprintf("Error collecting result files: %s", err))
 		return nil
 	}
 
-	return &Artifact{b.config.OutputDir, files}
+	return &Artifact{artifactDir, files}
 }
 
 func (b *Builder) Cancel() {
@@ -198,13 +278,3 @@ func (b *Builder) Cancel() {
 		b.runner.Cancel()
 	}
 }
-
-func (b *Builder) newDriver() (Driver, error) {
-	fusionAppPath := "/Applications/VMware Fusion.app"
-	driver := &Fusion5Driver{fusionAppPath}
-	if err := driver.Verify(); err != nil {
-		return nil, err
-	}
-
-	return driver, nil
-}
\ No newline at end of file
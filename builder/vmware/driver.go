@@ -0,0 +1,103 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/packer/packer"
+)
+
+// Driver is the interface that has to be implemented to communicate
+// with the underlying virtualization tooling (VMware Fusion, Workstation,
+// or Player) for the VMware builder to function properly.
+//
+// The Driver interface is separate from the Builder itself so that
+// the same builder steps can run on top of any supported VMware
+// product without needing to know which one is actually installed.
+type Driver interface {
+	// CreateDisk creates a virtual disk with the given size (such as
+	// "40000M") and adapter type ("lsilogic", "buslogic", etc.) at
+	// the given output path.
+	CreateDisk(output string, size string, adapterType string, typeId string) error
+
+	// IsRunning checks if the VMX file at the given path is running.
+	IsRunning(vmxPath string) (bool, error)
+
+	// Start starts a VM specified by the path to the VMX given.
+	Start(vmxPath string, headless bool) error
+
+	// Stop stops a VM specified by the path to the VMX given.
+	Stop(vmxPath string) error
+
+	// SuppressMessages modifies the VMX or surrounding directory so
+	// that VMware doesn't show any annoying messages.
+	SuppressMessages(vmxPath string) error
+
+	// ToolsIsoPath returns the path to the ISO that can be used to
+	// install VMware tools for the given guest OS type.
+	ToolsIsoPath(osType string) string
+
+	// Export converts the VM at vmxPath into an OVF or OVA bundle at
+	// outputPath using ovftool. format is "ovf" or "ova".
+	Export(vmxPath string, outputPath string, format string) error
+
+	// Verify checks to make sure that this driver should function
+	// properly. This should check that all the files it needs to
+	// access are available and are executable.
+	Verify() error
+}
+
+// driverProbe is a candidate Driver constructor that the registry tries
+// in order when the user asks for "auto" detection.
+type driverProbe struct {
+	Key     string
+	Factory func(config *config) Driver
+}
+
+// driverRegistry lists the drivers this builder knows how to create, in
+// the order they're tried during "auto" detection. Platform-specific
+// files (driver_fusion.go, driver_workstation.go, driver_player.go)
+// register themselves here via init().
+var driverRegistry = make([]driverProbe, 0, 3)
+
+func registerDriver(key string, factory func(config *config) Driver) {
+	driverRegistry = append(driverRegistry, driverProbe{key, factory})
+}
+
+// newDriver returns the Driver configured by the user, probing all known
+// drivers and picking the first one that verifies successfully when the
+// config says "auto" (or leaves the driver key blank).
+func (b *Builder) newDriver() (Driver, error) {
+	driverType := b.config.DriverType
+	if driverType == "" {
+		driverType = "auto"
+	}
+
+	if driverType != "auto" {
+		for _, probe := range driverRegistry {
+			if probe.Key != driverType {
+				continue
+			}
+
+			driver := probe.Factory(&b.config)
+			if err := driver.Verify(); err != nil {
+				return nil, err
+			}
+
+			return driver, nil
+		}
+
+		return nil, fmt.Errorf("unknown driver type: %s", driverType)
+	}
+
+	errs := make([]error, 0, len(driverRegistry))
+	for _, probe := range driverRegistry {
+		driver := probe.Factory(&b.config)
+		if err := driver.Verify(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", probe.Key, err))
+			continue
+		}
+
+		return driver, nil
+	}
+
+	return nil, &packer.MultiError{errs}
+}
@@ -0,0 +1,90 @@
+package vmware
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+func init() {
+	registerDriver("fusion5", func(config *config) Driver {
+		appPath := config.FusionAppPath
+		if appPath == "" {
+			appPath = "/Applications/VMware Fusion.app"
+		}
+
+		return &Fusion5Driver{appPath}
+	})
+}
+
+// Fusion5Driver talks to the VMware Fusion 5 binaries bundled inside the
+// Fusion.app package on macOS.
+type Fusion5Driver struct {
+	// AppPath is the path to the VMware Fusion application bundle.
+	AppPath string
+}
+
+func (d *Fusion5Driver) CreateDisk(output string, size string, adapterType string, typeId string) error {
+	return runAndLog(d.vdiskManagerPath(), "-c", "-s", size, "-a", adapterType, "-t", typeId, output)
+}
+
+func (d *Fusion5Driver) IsRunning(vmxPath string) (bool, error) {
+	out, err := exec.Command(d.vmrunPath(), "list").CombinedOutput()
+	if err != nil {
+		return false, err
+	}
+
+	return stringContainsLine(string(out), vmxPath), nil
+}
+
+func (d *Fusion5Driver) Start(vmxPath string, headless bool) error {
+	guiArg := "gui"
+	if headless {
+		guiArg = "nogui"
+	}
+
+	return runAndLog(d.vmrunPath(), "start", vmxPath, guiArg)
+}
+
+func (d *Fusion5Driver) Stop(vmxPath string) error {
+	return runAndLog(d.vmrunPath(), "stop", vmxPath, "hard")
+}
+
+func (d *Fusion5Driver) SuppressMessages(vmxPath string) error {
+	return nil
+}
+
+func (d *Fusion5Driver) ToolsIsoPath(osType string) string {
+	return filepath.Join(d.AppPath, "Contents", "Library", "isoimages", osType+".iso")
+}
+
+func (d *Fusion5Driver) Export(vmxPath string, outputPath string, format string) error {
+	return runAndLog(d.ovftoolPath(), fmt.Sprintf("--targetType=%s", format), vmxPath, outputPath)
+}
+
+func (d *Fusion5Driver) ovftoolPath() string {
+	return "/Applications/VMware OVF Tool/ovftool"
+}
+
+func (d *Fusion5Driver) Verify() error {
+	if _, err := os.Stat(d.AppPath); err != nil {
+		return fmt.Errorf("VMware Fusion not detected at %s", d.AppPath)
+	}
+
+	for _, path := range []string{d.vmrunPath(), d.vdiskManagerPath()} {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%s does not exist", path)
+		}
+	}
+
+	return nil
+}
+
+func (d *Fusion5Driver) vmrunPath() string {
+	return filepath.Join(d.AppPath, "Contents", "Library", "vmrun")
+}
+
+func (d *Fusion5Driver) vdiskManagerPath() string {
+	return filepath.Join(d.AppPath, "Contents", "Library", "vmware-vdiskmanager")
+}
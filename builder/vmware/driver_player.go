@@ -0,0 +1,60 @@
+package vmware
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func init() {
+	registerDriver("player5", func(config *config) Driver {
+		return &Player5Driver{}
+	})
+}
+
+// Player5Driver drives VMware Player 5, which ships the same vmrun and
+// vmware-vdiskmanager CLI tools as Workstation but has no ovftool and no
+// support for running headless.
+type Player5Driver struct{}
+
+func (d *Player5Driver) CreateDisk(output string, size string, adapterType string, typeId string) error {
+	return runAndLog("vmware-vdiskmanager", "-c", "-s", size, "-a", adapterType, "-t", typeId, output)
+}
+
+func (d *Player5Driver) IsRunning(vmxPath string) (bool, error) {
+	out, err := exec.Command("vmrun", "list").CombinedOutput()
+	if err != nil {
+		return false, err
+	}
+
+	return stringContainsLine(string(out), vmxPath), nil
+}
+
+func (d *Player5Driver) Start(vmxPath string, headless bool) error {
+	return runAndLog("vmrun", "start", vmxPath, "gui")
+}
+
+func (d *Player5Driver) Stop(vmxPath string) error {
+	return runAndLog("vmrun", "stop", vmxPath, "hard")
+}
+
+func (d *Player5Driver) SuppressMessages(vmxPath string) error {
+	return nil
+}
+
+func (d *Player5Driver) ToolsIsoPath(osType string) string {
+	return osType + ".iso"
+}
+
+func (d *Player5Driver) Export(vmxPath string, outputPath string, format string) error {
+	return fmt.Errorf("VMware Player does not support exporting to OVF/OVA")
+}
+
+func (d *Player5Driver) Verify() error {
+	for _, bin := range []string{"vmrun", "vmware-vdiskmanager"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			return fmt.Errorf("%s not found on the PATH", bin)
+		}
+	}
+
+	return nil
+}
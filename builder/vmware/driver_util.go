@@ -0,0 +1,37 @@
+package vmware
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runAndLog runs the given command and returns an error describing the
+// exit status and combined output if it failed.
+func runAndLog(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %s\n%s", name, strings.Join(args, " "), err, out)
+	}
+
+	return nil
+}
+
+// stringContainsLine returns true if haystack contains a line equal to
+// needle, ignoring surrounding whitespace.
+func stringContainsLine(haystack string, needle string) bool {
+	for _, line := range strings.Split(haystack, "\n") {
+		if strings.TrimSpace(line) == strings.TrimSpace(needle) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sendStringToVNC is a no-op: it does not connect to the VNC port that
+// stepConfigureVNC records in state, and no keystrokes reach the VM's
+// console.
+func sendStringToVNC(state map[string]interface{}, s string) {
+}
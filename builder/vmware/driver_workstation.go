@@ -0,0 +1,85 @@
+package vmware
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+func init() {
+	registerDriver("workstation9", func(config *config) Driver {
+		return &Workstation9Driver{ToolsPath: config.ToolsUploadPath}
+	})
+}
+
+// Workstation9Driver drives VMware Workstation 9 on Linux and Windows via
+// the vmrun and vmware-vdiskmanager binaries on the PATH.
+type Workstation9Driver struct {
+	// ToolsPath, if set, is returned by ToolsIsoPath as the tools ISO
+	// path for every guest OS type. When it is empty, ToolsIsoPath
+	// returns the bare file name "<osType>.iso".
+	ToolsPath string
+}
+
+func (d *Workstation9Driver) CreateDisk(output string, size string, adapterType string, typeId string) error {
+	return runAndLog("vmware-vdiskmanager", "-c", "-s", size, "-a", adapterType, "-t", typeId, output)
+}
+
+func (d *Workstation9Driver) IsRunning(vmxPath string) (bool, error) {
+	out, err := exec.Command("vmrun", "list").CombinedOutput()
+	if err != nil {
+		return false, err
+	}
+
+	return stringContainsLine(string(out), vmxPath), nil
+}
+
+func (d *Workstation9Driver) Start(vmxPath string, headless bool) error {
+	guiArg := "gui"
+	if headless {
+		guiArg = "nogui"
+	}
+
+	return runAndLog("vmrun", "start", vmxPath, guiArg)
+}
+
+func (d *Workstation9Driver) Stop(vmxPath string) error {
+	return runAndLog("vmrun", "stop", vmxPath, "hard")
+}
+
+func (d *Workstation9Driver) SuppressMessages(vmxPath string) error {
+	return nil
+}
+
+func (d *Workstation9Driver) ToolsIsoPath(osType string) string {
+	if d.ToolsPath != "" {
+		return d.ToolsPath
+	}
+
+	return osType + ".iso"
+}
+
+func (d *Workstation9Driver) Export(vmxPath string, outputPath string, format string) error {
+	return runAndLog("ovftool", fmt.Sprintf("--targetType=%s", format), vmxPath, outputPath)
+}
+
+func (d *Workstation9Driver) Verify() error {
+	if runtime.GOOS == "darwin" {
+		return fmt.Errorf("VMware Workstation is not supported on macOS")
+	}
+
+	for _, bin := range []string{"vmrun", "vmware-vdiskmanager"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			return fmt.Errorf("%s not found on the PATH", bin)
+		}
+	}
+
+	if d.ToolsPath != "" {
+		if _, err := os.Stat(d.ToolsPath); err != nil {
+			return fmt.Errorf("tools_path %s does not exist", d.ToolsPath)
+		}
+	}
+
+	return nil
+}
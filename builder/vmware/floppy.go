@@ -0,0 +1,199 @@
+package vmware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// These constants describe the standard 1.44MB FAT12 floppy geometry:
+// 512-byte sectors, 1 sector per cluster, 1 reserved (boot) sector,
+// 2 FAT copies of 9 sectors each, and a 224-entry root directory.
+const (
+	floppyBytesPerSector  = 512
+	floppySectorCount     = 2880
+	floppyReservedSectors = 1
+	floppyFATCount        = 2
+	floppySectorsPerFAT   = 9
+	floppyRootEntries     = 224
+	floppyImageSize       = floppySectorCount * floppyBytesPerSector
+
+	floppyRootDirSectors  = (floppyRootEntries*32 + floppyBytesPerSector - 1) / floppyBytesPerSector
+	floppyDataStartSector = floppyReservedSectors + floppyFATCount*floppySectorsPerFAT + floppyRootDirSectors
+	floppyDirEntrySize    = 32
+)
+
+// newFloppyFromFiles creates a 1.44MB FAT12-formatted floppy image
+// containing the given files, placed flat in the root directory, and
+// returns the path to the resulting image. The image is created inside
+// dir.
+func newFloppyFromFiles(dir string, files []string) (string, error) {
+	if len(files) > floppyRootEntries {
+		return "", fmt.Errorf("too many floppy_files: max %d", floppyRootEntries)
+	}
+
+	image := make([]byte, floppyImageSize)
+	writeFloppyBootSector(image)
+
+	fat := make([]uint16, floppySectorsPerFAT*floppyBytesPerSector/3*2)
+	fat[0] = 0xFF0
+	fat[1] = 0xFFF
+
+	rootDir := make([]byte, floppyRootDirSectors*floppyBytesPerSector)
+	nextCluster := uint16(2)
+	nextDataSector := floppyDataStartSector
+	seenNames := make(map[string]string)
+
+	for i, path := range files {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		name8 := string(to8Dot3(path))
+		if other, ok := seenNames[name8]; ok {
+			return "", fmt.Errorf("floppy_files %q and %q both map to the 8.3 filename %q", other, path, strings.TrimRight(name8, " "))
+		}
+		seenNames[name8] = path
+
+		clustersNeeded := (len(contents) + floppyBytesPerSector - 1) / floppyBytesPerSector
+		if clustersNeeded == 0 {
+			clustersNeeded = 1
+		}
+
+		if nextDataSector+clustersNeeded > floppySectorCount {
+			return "", fmt.Errorf("floppy_files exceed the 1.44MB image capacity")
+		}
+
+		firstCluster := nextCluster
+		cluster := firstCluster
+		for c := 0; c < clustersNeeded; c++ {
+			offset := (len(contents) - c*floppyBytesPerSector)
+			chunk := contents[c*floppyBytesPerSector:]
+			if offset > floppyBytesPerSector {
+				chunk = chunk[:floppyBytesPerSector]
+			}
+
+			copy(image[nextDataSector*floppyBytesPerSector:], chunk)
+			nextDataSector++
+
+			if c == clustersNeeded-1 {
+				fat[cluster] = 0xFFF
+			} else {
+				fat[cluster] = cluster + 1
+			}
+
+			cluster++
+		}
+
+		nextCluster += uint16(clustersNeeded)
+
+		writeFloppyDirEntry(rootDir[i*floppyDirEntrySize:], filepath.Base(path), firstCluster, uint32(len(contents)))
+	}
+
+	fatBytes := encodeFAT12(fat)
+	copy(image[floppyReservedSectors*floppyBytesPerSector:], fatBytes)
+	copy(image[(floppyReservedSectors+floppySectorsPerFAT)*floppyBytesPerSector:], fatBytes)
+	copy(image[(floppyReservedSectors+2*floppySectorsPerFAT)*floppyBytesPerSector:], rootDir)
+
+	imagePath := filepath.Join(dir, "floppy.flp")
+	if err := ioutil.WriteFile(imagePath, image, 0644); err != nil {
+		return "", fmt.Errorf("Error writing floppy image: %s", err)
+	}
+
+	return imagePath, nil
+}
+
+// writeFloppyBootSector fills in the BIOS parameter block for a
+// standard 1.44MB floppy. The boot code itself is left as zeroes since
+// this image is only ever attached as a data/answer-file floppy, never
+// booted from.
+func writeFloppyBootSector(image []byte) {
+	image[0] = 0xEB
+	image[1] = 0x3C
+	image[2] = 0x90
+	copy(image[3:11], []byte("PACKER  "))
+
+	putUint16(image[11:], floppyBytesPerSector)
+	image[13] = 1 // sectors per cluster
+	putUint16(image[14:], floppyReservedSectors)
+	image[16] = floppyFATCount
+	putUint16(image[17:], floppyRootEntries)
+	putUint16(image[19:], floppySectorCount)
+	image[21] = 0xF0 // media descriptor: 3.5" 1.44MB floppy
+	putUint16(image[22:], floppySectorsPerFAT)
+	putUint16(image[24:], 18) // sectors per track
+	putUint16(image[26:], 2)  // number of heads
+
+	image[510] = 0x55
+	image[511] = 0xAA
+}
+
+// writeFloppyDirEntry writes a single 32-byte FAT12 root directory
+// entry for name, starting at firstCluster and sized size bytes.
+func writeFloppyDirEntry(entry []byte, name string, firstCluster uint16, size uint32) {
+	copy(entry[0:11], to8Dot3(name))
+	entry[11] = 0x20 // archive attribute
+	putUint16(entry[26:], firstCluster)
+	putUint32(entry[28:], size)
+}
+
+// to8Dot3 converts an arbitrary filename into a space-padded, upper-cased
+// 8.3 FAT name (11 bytes, no dot).
+func to8Dot3(name string) []byte {
+	base := strings.ToUpper(filepath.Base(name))
+	ext := ""
+	if idx := strings.LastIndex(base, "."); idx >= 0 {
+		ext = base[idx+1:]
+		base = base[:idx]
+	}
+
+	if len(base) > 8 {
+		base = base[:8]
+	}
+
+	if len(ext) > 3 {
+		ext = ext[:3]
+	}
+
+	out := []byte("           ")
+	copy(out[0:8], base)
+	copy(out[8:11], ext)
+
+	return out
+}
+
+// encodeFAT12 packs 12-bit FAT entries two-at-a-time into 3 bytes each,
+// the on-disk FAT12 representation, zero-padded to a full FAT region.
+func encodeFAT12(entries []uint16) []byte {
+	out := make([]byte, floppySectorsPerFAT*floppyBytesPerSector)
+
+	for i := 0; i+1 < len(entries); i += 2 {
+		e0 := entries[i]
+		e1 := entries[i+1]
+
+		byteIdx := (i / 2) * 3
+		if byteIdx+2 >= len(out) {
+			break
+		}
+
+		out[byteIdx] = byte(e0 & 0xFF)
+		out[byteIdx+1] = byte((e0>>8)&0x0F) | byte((e1&0x0F)<<4)
+		out[byteIdx+2] = byte((e1 >> 4) & 0xFF)
+	}
+
+	return out
+}
+
+func putUint16(b []byte, v uint16) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+}
+
+func putUint32(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+	b[3] = byte(v >> 24)
+}
@@ -0,0 +1,125 @@
+package vmware
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodeFAT12(t *testing.T) {
+	entries := []uint16{0xFF0, 0xFFF, 0x003, 0x004, 0xFFF, 0x001}
+
+	out := encodeFAT12(entries)
+
+	decoded := decodeFAT12(out, len(entries))
+	for i, want := range entries {
+		if decoded[i] != want {
+			t.Errorf("entry %d: got %#x, want %#x", i, decoded[i], want)
+		}
+	}
+}
+
+// decodeFAT12 is the inverse of encodeFAT12, used only by tests to verify
+// the packed on-disk representation round-trips.
+func decodeFAT12(b []byte, count int) []uint16 {
+	out := make([]uint16, count)
+	for i := 0; i < count; i += 2 {
+		byteIdx := (i / 2) * 3
+		out[i] = uint16(b[byteIdx]) | (uint16(b[byteIdx+1]&0x0F) << 8)
+		if i+1 < count {
+			out[i+1] = uint16(b[byteIdx+1]>>4) | (uint16(b[byteIdx+2]) << 4)
+		}
+	}
+
+	return out
+}
+
+func TestTo8Dot3(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"config.xml", "CONFIG  XML"},
+		{"a.txt", "A       TXT"},
+		{"answer-file-longname.cfg", "ANSWER-FCFG"},
+		{"noext", "NOEXT      "},
+	}
+
+	for _, c := range cases {
+		got := string(to8Dot3(c.name))
+		if got != c.want {
+			t.Errorf("to8Dot3(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNewFloppyFromFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-floppy-test")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file1 := filepath.Join(dir, "a.txt")
+	file2 := filepath.Join(dir, "b.txt")
+	if err := ioutil.WriteFile(file1, []byte("hello from a"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	if err := ioutil.WriteFile(file2, []byte("hello from b"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	imagePath, err := newFloppyFromFiles(dir, []string{file1, file2})
+	if err != nil {
+		t.Fatalf("newFloppyFromFiles: %s", err)
+	}
+
+	image, err := ioutil.ReadFile(imagePath)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+
+	if len(image) != floppyImageSize {
+		t.Fatalf("image size = %d, want %d", len(image), floppyImageSize)
+	}
+
+	if image[510] != 0x55 || image[511] != 0xAA {
+		t.Errorf("boot sector signature missing")
+	}
+
+	rootDir := image[(floppyReservedSectors+2*floppySectorsPerFAT)*floppyBytesPerSector:]
+
+	name0 := string(rootDir[0:8])
+	ext0 := string(rootDir[8:11])
+	if name0 != "A       " || ext0 != "TXT" {
+		t.Errorf("first dir entry name = %q.%q, want \"A\".\"TXT\"", name0, ext0)
+	}
+
+	name1 := string(rootDir[32 : 32+8])
+	ext1 := string(rootDir[32+8 : 32+11])
+	if name1 != "B       " || ext1 != "TXT" {
+		t.Errorf("second dir entry name = %q.%q, want \"B\".\"TXT\"", name1, ext1)
+	}
+}
+
+func TestNewFloppyFromFilesRejectsNameCollisions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-floppy-test")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file1 := filepath.Join(dir, "a.txt")
+	file2 := filepath.Join(dir, "A.TXT")
+	if err := ioutil.WriteFile(file1, []byte("one"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	if err := ioutil.WriteFile(file2, []byte("two"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	if _, err := newFloppyFromFiles(dir, []string{file1, file2}); err == nil {
+		t.Fatalf("expected an error for colliding 8.3 names, got nil")
+	}
+}
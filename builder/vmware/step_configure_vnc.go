@@ -0,0 +1,58 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"io/ioutil"
+	"math/rand"
+	"net"
+)
+
+// stepConfigureVNC finds a free VNC port within the configured range and
+// appends the settings that enable VNC to the VMX file.
+type stepConfigureVNC struct{}
+
+func (s *stepConfigureVNC) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	ui := state["ui"].(packer.Ui)
+	vmxPath := state["vmx_path"].(string)
+
+	var vncPort uint
+	var foundPort bool
+	portRange := int(config.VNCPortMax - config.VNCPortMin)
+	for i := 0; i < 10; i++ {
+		vncPort = config.VNCPortMin + uint(rand.Intn(portRange+1))
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", vncPort))
+		if err == nil {
+			l.Close()
+			foundPort = true
+			break
+		}
+	}
+
+	if !foundPort {
+		state["error"] = fmt.Errorf("Unable to find an available port for VNC")
+		return multistep.ActionHalt
+	}
+
+	ui.Say(fmt.Sprintf("Configuring VNC on port %d...", vncPort))
+
+	f, err := ioutil.ReadFile(vmxPath)
+	if err != nil {
+		state["error"] = fmt.Errorf("Error reading VMX for VNC: %s", err)
+		return multistep.ActionHalt
+	}
+
+	contents := string(f) + fmt.Sprintf("\nRemoteDisplay.vnc.enabled = \"TRUE\"\nRemoteDisplay.vnc.port = \"%d\"\n", vncPort)
+	if err := ioutil.WriteFile(vmxPath, []byte(contents), 0644); err != nil {
+		state["error"] = fmt.Errorf("Error writing VMX for VNC: %s", err)
+		return multistep.ActionHalt
+	}
+
+	state["vnc_port"] = vncPort
+
+	return multistep.ActionContinue
+}
+
+func (s *stepConfigureVNC) Cleanup(state map[string]interface{}) {}
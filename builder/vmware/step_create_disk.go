@@ -0,0 +1,67 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"path/filepath"
+)
+
+// diskTypeIdToVdiskManagerType translates the user-facing disk_type_id
+// values into the -t codes vmware-vdiskmanager expects.
+func diskTypeIdToVdiskManagerType(typeId string) (string, error) {
+	switch typeId {
+	case "thin":
+		return "0", nil
+	case "2GbMaxExtentSparse":
+		return "1", nil
+	case "thick":
+		return "2", nil
+	default:
+		return "", fmt.Errorf("disk_type_id must be one of: thin, thick, 2GbMaxExtentSparse")
+	}
+}
+
+// stepCreateDisk creates the primary virtual disk plus any
+// additional_disks for the VM using the configured Driver.
+type stepCreateDisk struct{}
+
+func (s *stepCreateDisk) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	driver := state["driver"].(Driver)
+	ui := state["ui"].(packer.Ui)
+
+	diskFilenames := make([]string, 0, 1+len(config.AdditionalDisks))
+
+	diskTypeId, _ := diskTypeIdToVdiskManagerType(config.DiskTypeId)
+	diskFilename := fmt.Sprintf("%s.vmdk", config.DiskName)
+	diskFullPath := filepath.Join(config.OutputDir, diskFilename)
+
+	ui.Say("Creating hard drive...")
+	if err := driver.CreateDisk(diskFullPath, fmt.Sprintf("%dM", config.DiskSize), "lsilogic", diskTypeId); err != nil {
+		state["error"] = fmt.Errorf("Error creating hard drive: %s", err)
+		return multistep.ActionHalt
+	}
+
+	diskFilenames = append(diskFilenames, diskFilename)
+
+	for _, additional := range config.AdditionalDisks {
+		additionalTypeId, _ := diskTypeIdToVdiskManagerType(additional.Type)
+		additionalFilename := fmt.Sprintf("%s.vmdk", additional.Name)
+		additionalFullPath := filepath.Join(config.OutputDir, additionalFilename)
+
+		ui.Say(fmt.Sprintf("Creating additional hard drive %s...", additional.Name))
+		if err := driver.CreateDisk(additionalFullPath, fmt.Sprintf("%dM", additional.Size), "lsilogic", additionalTypeId); err != nil {
+			state["error"] = fmt.Errorf("Error creating additional hard drive %s: %s", additional.Name, err)
+			return multistep.ActionHalt
+		}
+
+		diskFilenames = append(diskFilenames, additionalFilename)
+	}
+
+	state["disk_filenames"] = diskFilenames
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCreateDisk) Cleanup(state map[string]interface{}) {}
@@ -0,0 +1,47 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"os"
+)
+
+// stepCreateFloppy builds a floppy image out of config.FloppyFiles so
+// that unattended install answer files (such as a Windows
+// Autounattend.xml or a Linux preseed file) can be picked up by the OS
+// installer without needing an HTTP server.
+type stepCreateFloppy struct {
+	floppyPath string
+}
+
+func (s *stepCreateFloppy) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	ui := state["ui"].(packer.Ui)
+
+	if len(config.FloppyFiles) == 0 {
+		return multistep.ActionContinue
+	}
+
+	ui.Say("Creating floppy disk...")
+
+	floppyPath, err := newFloppyFromFiles(config.OutputDir, config.FloppyFiles)
+	if err != nil {
+		state["error"] = fmt.Errorf("Error creating floppy: %s", err)
+		return multistep.ActionHalt
+	}
+
+	s.floppyPath = floppyPath
+	state["floppy_path"] = floppyPath
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCreateFloppy) Cleanup(state map[string]interface{}) {
+	_, cancelled := state[multistep.StateCancelled]
+	_, halted := state[multistep.StateHalted]
+
+	if (cancelled || halted) && s.floppyPath != "" {
+		os.Remove(s.floppyPath)
+	}
+}
@@ -0,0 +1,68 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// stepCreateVMX renders the VMX file for the machine and writes it to
+// the output directory.
+type stepCreateVMX struct{}
+
+func (s *stepCreateVMX) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	ui := state["ui"].(packer.Ui)
+
+	ui.Say("Building and writing VMX file...")
+
+	vmxData := map[string]string{
+		"displayName": config.VMName,
+		"guestOS":     config.GuestOSType,
+	}
+
+	diskFilenames := state["disk_filenames"].([]string)
+	for i, diskFilename := range diskFilenames {
+		vmxData[fmt.Sprintf("scsi0:%d.present", i)] = "TRUE"
+		vmxData[fmt.Sprintf("scsi0:%d.fileName", i)] = diskFilename
+	}
+
+	if floppyPathRaw, ok := state["floppy_path"]; ok {
+		vmxData["floppy0.present"] = "TRUE"
+		vmxData["floppy0.fileType"] = "file"
+		vmxData["floppy0.fileName"] = filepath.Base(floppyPathRaw.(string))
+	}
+
+	for k, v := range config.VMXData {
+		vmxData[k] = v
+	}
+
+	vmxContents := ""
+	for k, v := range vmxData {
+		vmxContents += fmt.Sprintf("%s = \"%s\"\n", k, v)
+	}
+
+	vmxPath := filepath.Join(config.OutputDir, fmt.Sprintf("%s.vmx", config.VMName))
+	if err := ioutil.WriteFile(vmxPath, []byte(vmxContents), 0644); err != nil {
+		state["error"] = fmt.Errorf("Error writing VMX: %s", err)
+		return multistep.ActionHalt
+	}
+
+	state["vmx_path"] = vmxPath
+
+	return multistep.ActionContinue
+}
+
+func (s *stepCreateVMX) Cleanup(state map[string]interface{}) {
+	_, cancelled := state[multistep.StateCancelled]
+	_, halted := state[multistep.StateHalted]
+
+	if cancelled || halted {
+		if vmxPathRaw, ok := state["vmx_path"]; ok {
+			os.Remove(vmxPathRaw.(string))
+		}
+	}
+}
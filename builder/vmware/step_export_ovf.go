@@ -0,0 +1,52 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"os"
+	"path/filepath"
+)
+
+// stepExportOVF converts the finished VMX into a portable OVF or OVA
+// bundle via ovftool, when config.Format asks for one. It's a no-op for
+// the default "vmx" format.
+//
+// The export is written to its own "export" subdirectory of the output
+// directory rather than alongside the VMX because ovftool's "ovf" format
+// writes out several
+// files (the .ovf descriptor, one or more .vmdk disks, and a .mf
+// manifest) instead of a single bundle, and the artifact needs to
+// reflect all of them.
+type stepExportOVF struct{}
+
+func (s *stepExportOVF) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	driver := state["driver"].(Driver)
+	ui := state["ui"].(packer.Ui)
+	vmxPath := state["vmx_path"].(string)
+
+	if config.Format == "vmx" {
+		return multistep.ActionContinue
+	}
+
+	ui.Say(fmt.Sprintf("Exporting virtual machine to %s...", config.Format))
+
+	exportDir := filepath.Join(config.OutputDir, "export")
+	if err := os.MkdirAll(exportDir, 0755); err != nil {
+		state["error"] = fmt.Errorf("Error creating export directory: %s", err)
+		return multistep.ActionHalt
+	}
+
+	outputPath := filepath.Join(exportDir, fmt.Sprintf("%s.%s", config.VMName, config.Format))
+	if err := driver.Export(vmxPath, outputPath, config.Format); err != nil {
+		state["error"] = fmt.Errorf("Error exporting to %s: %s", config.Format, err)
+		return multistep.ActionHalt
+	}
+
+	state["export_path"] = exportDir
+
+	return multistep.ActionContinue
+}
+
+func (s *stepExportOVF) Cleanup(state map[string]interface{}) {}
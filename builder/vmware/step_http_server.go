@@ -0,0 +1,56 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"math/rand"
+	"net"
+	"net/http"
+)
+
+// stepHTTPServer serves config.HTTPDir over HTTP on a random port within
+// the configured range, so that boot commands can fetch preseed/kickstart
+// files during the OS install.
+type stepHTTPServer struct {
+	l net.Listener
+}
+
+func (s *stepHTTPServer) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	ui := state["ui"].(packer.Ui)
+
+	if config.HTTPDir == "" {
+		state["http_port"] = uint(0)
+		return multistep.ActionContinue
+	}
+
+	var httpPort uint
+	portRange := int(config.HTTPPortMax - config.HTTPPortMin)
+	for i := 0; i < 10; i++ {
+		httpPort = config.HTTPPortMin + uint(rand.Intn(portRange+1))
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", httpPort))
+		if err == nil {
+			s.l = l
+			break
+		}
+	}
+
+	if s.l == nil {
+		state["error"] = fmt.Errorf("Unable to find an available port for the HTTP server")
+		return multistep.ActionHalt
+	}
+
+	ui.Say(fmt.Sprintf("Starting HTTP server on port %d...", httpPort))
+	go http.Serve(s.l, http.FileServer(http.Dir(config.HTTPDir)))
+
+	state["http_port"] = httpPort
+
+	return multistep.ActionContinue
+}
+
+func (s *stepHTTPServer) Cleanup(state map[string]interface{}) {
+	if s.l != nil {
+		s.l.Close()
+	}
+}
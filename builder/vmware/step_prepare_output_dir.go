@@ -0,0 +1,43 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"os"
+)
+
+// stepPrepareOutputDir creates the directory that the VM files will be
+// written to, removing any stale directory from a previous run first.
+type stepPrepareOutputDir struct{}
+
+func (s *stepPrepareOutputDir) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	ui := state["ui"].(packer.Ui)
+
+	if _, err := os.Stat(config.OutputDir); err == nil {
+		ui.Say(fmt.Sprintf("Deleting previous output directory: %s", config.OutputDir))
+		os.RemoveAll(config.OutputDir)
+	}
+
+	ui.Say("Creating output directory...")
+	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+		state["error"] = fmt.Errorf("Error creating output directory: %s", err)
+		return multistep.ActionHalt
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepPrepareOutputDir) Cleanup(state map[string]interface{}) {
+	_, cancelled := state[multistep.StateCancelled]
+	_, halted := state[multistep.StateHalted]
+
+	if cancelled || halted {
+		config := state["config"].(*config)
+		ui := state["ui"].(packer.Ui)
+
+		ui.Say("Deleting output directory...")
+		os.RemoveAll(config.OutputDir)
+	}
+}
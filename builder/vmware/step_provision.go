@@ -0,0 +1,24 @@
+package vmware
+
+import (
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+)
+
+// stepProvision runs the packer.Hook attached to the build, giving any
+// configured provisioners a chance to run against the booted guest.
+type stepProvision struct{}
+
+func (s *stepProvision) Run(state map[string]interface{}) multistep.StepAction {
+	hook := state["hook"].(packer.Hook)
+	ui := state["ui"].(packer.Ui)
+
+	if err := hook.Run(packer.HookProvision, ui, nil, state); err != nil {
+		state["error"] = err
+		return multistep.ActionHalt
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepProvision) Cleanup(state map[string]interface{}) {}
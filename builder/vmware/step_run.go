@@ -0,0 +1,40 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+)
+
+// stepRun starts the virtual machine.
+type stepRun struct {
+	vmxPath string
+}
+
+func (s *stepRun) Run(state map[string]interface{}) multistep.StepAction {
+	driver := state["driver"].(Driver)
+	ui := state["ui"].(packer.Ui)
+	vmxPath := state["vmx_path"].(string)
+
+	ui.Say("Starting virtual machine...")
+	if err := driver.Start(vmxPath, false); err != nil {
+		state["error"] = fmt.Errorf("Error starting VM: %s", err)
+		return multistep.ActionHalt
+	}
+
+	s.vmxPath = vmxPath
+
+	return multistep.ActionContinue
+}
+
+func (s *stepRun) Cleanup(state map[string]interface{}) {
+	if s.vmxPath == "" {
+		return
+	}
+
+	driver := state["driver"].(Driver)
+	running, _ := driver.IsRunning(s.vmxPath)
+	if running {
+		driver.Stop(s.vmxPath)
+	}
+}
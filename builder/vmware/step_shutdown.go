@@ -0,0 +1,51 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"time"
+)
+
+// stepShutdown passes config.ShutdownCommand to sendStringToVNC (if set)
+// or stops the VM directly via the Driver, then waits for it to actually
+// power off before continuing.
+type stepShutdown struct{}
+
+func (s *stepShutdown) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	driver := state["driver"].(Driver)
+	ui := state["ui"].(packer.Ui)
+	vmxPath := state["vmx_path"].(string)
+
+	if config.ShutdownCommand != "" {
+		ui.Say("Gracefully halting virtual machine...")
+		sendStringToVNC(state, config.ShutdownCommand+"\n")
+	} else {
+		ui.Say("Forcibly halting virtual machine...")
+		if err := driver.Stop(vmxPath); err != nil {
+			state["error"] = fmt.Errorf("Error stopping VM: %s", err)
+			return multistep.ActionHalt
+		}
+	}
+
+	shutdownTimeout := time.After(config.ShutdownTimeout)
+	for {
+		running, _ := driver.IsRunning(vmxPath)
+		if !running {
+			break
+		}
+
+		select {
+		case <-shutdownTimeout:
+			state["error"] = fmt.Errorf("Timeout while waiting for VM to shut down")
+			return multistep.ActionHalt
+		default:
+			time.Sleep(1 * time.Second)
+		}
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepShutdown) Cleanup(state map[string]interface{}) {}
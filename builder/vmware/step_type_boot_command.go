@@ -0,0 +1,47 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"strings"
+	"time"
+)
+
+// stepTypeBootCommand waits config.BootWait and then passes each line of
+// the configured boot command to sendStringToVNC, replacing the literal
+// text {{.HTTPPort}} with the port of the HTTP server started by
+// stepHTTPServer. No other placeholder, such as {{.HTTPIP}}, is replaced.
+type stepTypeBootCommand struct{}
+
+func (s *stepTypeBootCommand) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	driver := state["driver"].(Driver)
+	ui := state["ui"].(packer.Ui)
+	vmxPath := state["vmx_path"].(string)
+	httpPort := state["http_port"].(uint)
+
+	if config.BootWait > 0 {
+		ui.Say(fmt.Sprintf("Waiting %s for boot...", config.BootWait))
+		time.Sleep(config.BootWait)
+	}
+
+	if len(config.BootCommand) == 0 {
+		return multistep.ActionContinue
+	}
+
+	if err := driver.SuppressMessages(vmxPath); err != nil {
+		state["error"] = fmt.Errorf("Error preparing to type boot command: %s", err)
+		return multistep.ActionHalt
+	}
+
+	ui.Say("Typing the boot command...")
+	for _, line := range config.BootCommand {
+		command := strings.Replace(line, "{{.HTTPPort}}", fmt.Sprintf("%d", httpPort), -1)
+		sendStringToVNC(state, command)
+	}
+
+	return multistep.ActionContinue
+}
+
+func (s *stepTypeBootCommand) Cleanup(state map[string]interface{}) {}
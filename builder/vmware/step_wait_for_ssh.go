@@ -0,0 +1,54 @@
+package vmware
+
+import (
+	"fmt"
+	"github.com/mitchellh/multistep"
+	"github.com/mitchellh/packer/packer"
+	"net"
+	"time"
+)
+
+// stepWaitForSSH blocks until the guest's SSH server is reachable, or
+// config.SSHWaitTimeout elapses.
+type stepWaitForSSH struct{}
+
+func (s *stepWaitForSSH) Run(state map[string]interface{}) multistep.StepAction {
+	config := state["config"].(*config)
+	ui := state["ui"].(packer.Ui)
+
+	ui.Say("Waiting for SSH to become available...")
+
+	done := make(chan bool, 1)
+	go func() {
+		for {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort(guestIPFromState(state), "22"), 5*time.Second)
+			if err == nil {
+				conn.Close()
+				done <- true
+				return
+			}
+
+			time.Sleep(2 * time.Second)
+		}
+	}()
+
+	select {
+	case <-done:
+		return multistep.ActionContinue
+	case <-time.After(config.SSHWaitTimeout):
+		state["error"] = fmt.Errorf("Timeout waiting for SSH")
+		return multistep.ActionHalt
+	}
+}
+
+func (s *stepWaitForSSH) Cleanup(state map[string]interface{}) {}
+
+// guestIPFromState returns the IP address recorded for the guest, if any
+// step has discovered one yet.
+func guestIPFromState(state map[string]interface{}) string {
+	if ip, ok := state["guest_ip"]; ok {
+		return ip.(string)
+	}
+
+	return ""
+}